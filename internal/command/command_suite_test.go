@@ -0,0 +1,98 @@
+package command_test
+
+import (
+	"sync"
+	"testing"
+
+	"code.cloudfoundry.org/cf-drain-cli/internal/drain"
+	"code.cloudfoundry.org/cli/plugin/models"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestCommand(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Command Suite")
+}
+
+type stubCliConnection struct {
+	mu sync.Mutex
+
+	cliCommandArgs [][]string
+
+	unbindServiceError  error
+	unbindServiceErrors map[string]error
+	deleteServiceError  error
+
+	getServicesName  string
+	getServicesApps  []string
+	getServicesError error
+}
+
+func newStubCliConnection() *stubCliConnection {
+	return &stubCliConnection{
+		unbindServiceErrors: make(map[string]error),
+	}
+}
+
+func (c *stubCliConnection) GetServices() ([]plugin_models.GetServices_Model, error) {
+	if c.getServicesError != nil {
+		return nil, c.getServicesError
+	}
+
+	return []plugin_models.GetServices_Model{
+		{
+			Name:             c.getServicesName,
+			ApplicationNames: c.getServicesApps,
+		},
+	}, nil
+}
+
+func (c *stubCliConnection) CliCommand(args ...string) ([]string, error) {
+	c.mu.Lock()
+	c.cliCommandArgs = append(c.cliCommandArgs, args)
+	c.mu.Unlock()
+
+	switch args[0] {
+	case "unbind-service":
+		if err, ok := c.unbindServiceErrors[args[1]]; ok {
+			return nil, err
+		}
+		if c.unbindServiceError != nil {
+			return nil, c.unbindServiceError
+		}
+	case "delete-service":
+		if c.deleteServiceError != nil {
+			return nil, c.deleteServiceError
+		}
+	}
+
+	return nil, nil
+}
+
+type stubDrainFetcher struct {
+	drains      []drain.Drain
+	orgDrains   []drain.Drain
+	drainsError error
+}
+
+func newStubDrainFetcher() *stubDrainFetcher {
+	return &stubDrainFetcher{}
+}
+
+func (f *stubDrainFetcher) Drains() ([]drain.Drain, error) {
+	if f.drainsError != nil {
+		return nil, f.drainsError
+	}
+	return f.drains, nil
+}
+
+func (f *stubDrainFetcher) DrainsInOrg() ([]drain.Drain, error) {
+	if f.drainsError != nil {
+		return nil, f.drainsError
+	}
+	if f.orgDrains != nil {
+		return f.orgDrains, nil
+	}
+	return f.drains, nil
+}
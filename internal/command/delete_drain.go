@@ -0,0 +1,424 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	flags "github.com/jessevdk/go-flags"
+
+	"code.cloudfoundry.org/cf-drain-cli/internal/drain"
+	"code.cloudfoundry.org/cli/plugin/models"
+)
+
+// cliConnection is the subset of plugin.CliConnection that the commands in
+// this package rely on.
+type cliConnection interface {
+	CliCommand(args ...string) ([]string, error)
+	GetServices() ([]plugin_models.GetServices_Model, error)
+}
+
+// DrainFetcher enumerates the syslog drain user-provided services created
+// by this plugin.
+type DrainFetcher interface {
+	// Drains returns the drains visible in the targeted space.
+	Drains() ([]drain.Drain, error)
+
+	// DrainsInOrg returns the drains visible across every space in the
+	// targeted org.
+	DrainsInOrg() ([]drain.Drain, error)
+}
+
+// orphanSchemes are the drain URL schemes that purge considers eligible for
+// cleanup. syslog and syslog-tls are schemes this plugin invented for its
+// own drains, so any app-less instance of either is unambiguously ours.
+// https is also used by this plugin's HTTPS drains, but it is equally the
+// scheme of any ordinary, unrelated user-provided service someone bound to
+// an app and later unbound — scheme alone can't tell those apart, so https
+// drains additionally need isPluginDrain to confirm real provenance.
+var orphanSchemes = map[string]bool{
+	"syslog":     true,
+	"syslog-tls": true,
+	"https":      true,
+}
+
+// isPluginDrain reports whether d carries provenance that could only come
+// from this plugin having created it, as opposed to an arbitrary
+// user-provided service that happens to share a drain's URL scheme. Type is
+// set by DrainFetcher only for services it recognizes as drains it manages.
+func isPluginDrain(d drain.Drain) bool {
+	return d.Type != ""
+}
+
+type deleteDrainOptions struct {
+	Force     bool   `short:"f"`
+	Purge     bool   `long:"purge"`
+	AllSpaces bool   `long:"all-spaces"`
+	Parallel  int    `long:"parallel" default:"4"`
+	Output    string `short:"o" long:"output"`
+}
+
+// deleteDrainResult is the --output json payload for DeleteDrain: the drain
+// that was targeted, the per-app unbind outcome, and whether the drain
+// itself was deleted.
+type deleteDrainResult struct {
+	Name   string          `json:"name"`
+	Guid   string          `json:"guid,omitempty"`
+	Type   string          `json:"type,omitempty"`
+	URL    string          `json:"url,omitempty"`
+	Apps   []appStepResult `json:"apps"`
+	Status string          `json:"status"`
+	Error  string          `json:"error,omitempty"`
+}
+
+type appStepResult struct {
+	Name   string `json:"name"`
+	Guid   string `json:"guid,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// DeleteDrain unbinds the named drain from every app it is bound to and
+// deletes the backing user-provided service. With --purge it instead
+// ignores the positional drain name and removes every orphaned drain (see
+// PurgeDrains). With --output json the y/N prompt is skipped (as with -f)
+// and the outcome is written to ui as a single JSON object instead of the
+// usual plain-text messages.
+func DeleteDrain(cli cliConnection, args []string, ui UI, df DrainFetcher) {
+	opts := deleteDrainOptions{}
+	args, err := flags.ParseArgs(&opts, args)
+	if err != nil {
+		failf(ui, opts.Output, "%s", err)
+	}
+
+	if opts.Purge {
+		purgeDrains(cli, ui, df, opts.Force, opts.AllSpaces, opts.Output)
+		return
+	}
+
+	if len(args) != 1 {
+		failf(ui, opts.Output, "Invalid arguments, expected 1, got %d.", len(args))
+	}
+	name := args[0]
+
+	services, err := cli.GetServices()
+	if err != nil {
+		failf(ui, opts.Output, "%s", err)
+	}
+
+	var apps []string
+	var found bool
+	for _, s := range services {
+		if s.Name == name {
+			apps = s.ApplicationNames
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		failf(ui, opts.Output, "Unable to find service %s.", name)
+	}
+
+	jsonOutput := opts.Output == "json"
+	appGuids, meta := lookupDrainMetadata(df, name)
+
+	if !opts.Force && !jsonOutput && !ui.Confirm(fmt.Sprintf(
+		"Are you sure you want to unbind %s from %s and delete %s? [y/N] ",
+		name, strings.Join(apps, ", "), name,
+	)) {
+		ui.Say("Delete cancelled")
+		return
+	}
+
+	unbound, failed := unbindApps(cli, name, apps, opts.Parallel)
+
+	if jsonOutput {
+		result := deleteDrainResult{
+			Name: name,
+			Guid: meta.Guid,
+			Type: meta.Type,
+			URL:  meta.DrainURL,
+			Apps: appStepResults(apps, unbound, failed, appGuids),
+		}
+
+		if len(failed) > 0 {
+			result.Status = "failed"
+			result.Error = failed[0].err.Error()
+			ui.Failed("%s", mustJSON(result))
+		}
+
+		if _, err := cli.CliCommand("delete-service", name, "-f"); err != nil {
+			result.Status = "failed"
+			result.Error = err.Error()
+			ui.Failed("%s", mustJSON(result))
+		}
+
+		result.Status = "deleted"
+		printJSON(ui, result)
+		return
+	}
+
+	if len(failed) > 0 {
+		reportUnbindFailures(ui, unbound, failed)
+		ui.Failed("%s", failed[0].err)
+	}
+
+	if _, err := cli.CliCommand("delete-service", name, "-f"); err != nil {
+		ui.Failed("%s", err)
+	}
+}
+
+func appStepResults(apps, unbound []string, failed []unbindFailure, guids map[string]string) []appStepResult {
+	failedByApp := make(map[string]error, len(failed))
+	for _, f := range failed {
+		failedByApp[f.app] = f.err
+	}
+	unboundSet := make(map[string]bool, len(unbound))
+	for _, app := range unbound {
+		unboundSet[app] = true
+	}
+
+	results := make([]appStepResult, 0, len(apps))
+	for _, app := range apps {
+		r := appStepResult{Name: app, Guid: guids[app]}
+		switch {
+		case failedByApp[app] != nil:
+			r.Status = "failed"
+			r.Error = failedByApp[app].Error()
+		case unboundSet[app]:
+			r.Status = "unbound"
+		default:
+			r.Status = "skipped"
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+func lookupDrainMetadata(df DrainFetcher, name string) (map[string]string, drain.Drain) {
+	guids := map[string]string{}
+
+	drains, err := df.Drains()
+	if err != nil {
+		return guids, drain.Drain{}
+	}
+
+	for _, d := range drains {
+		if d.Name != name {
+			continue
+		}
+		for i, app := range d.Apps {
+			if i < len(d.AppGuids) {
+				guids[app] = d.AppGuids[i]
+			}
+		}
+		return guids, d
+	}
+
+	return guids, drain.Drain{}
+}
+
+func printJSON(ui UI, v interface{}) {
+	ui.Say("%s", mustJSON(v))
+}
+
+func mustJSON(v interface{}) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return []byte(fmt.Sprintf(`{"error":%q}`, err))
+	}
+	return b
+}
+
+// failf reports a fatal error to ui either as the usual plain-text message
+// or, when output is "json", as a JSON error object.
+func failf(ui UI, output string, format string, a ...interface{}) {
+	if output == "json" {
+		ui.Failed("%s", mustJSON(map[string]string{"error": fmt.Sprintf(format, a...)}))
+		return
+	}
+	ui.Failed(format, a...)
+}
+
+type unbindFailure struct {
+	app string
+	err error
+}
+
+// unbindApps dispatches unbind-service calls across a bounded pool of
+// workers so that drains bound to many apps don't have to be unbound one at
+// a time. It returns the apps that were successfully unbound and the apps
+// that failed, both sorted by app name so callers get deterministic output.
+func unbindApps(cli cliConnection, name string, apps []string, parallel int) ([]string, []unbindFailure) {
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	jobs := make(chan string)
+	results := make(chan unbindFailure, len(apps))
+
+	var wg sync.WaitGroup
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for app := range jobs {
+				_, err := cli.CliCommand("unbind-service", app, name)
+				results <- unbindFailure{app: app, err: err}
+			}
+		}()
+	}
+
+	for _, app := range apps {
+		jobs <- app
+	}
+	close(jobs)
+
+	wg.Wait()
+	close(results)
+
+	var unbound []string
+	var failed []unbindFailure
+	for r := range results {
+		if r.err != nil {
+			failed = append(failed, r)
+		} else {
+			unbound = append(unbound, r.app)
+		}
+	}
+
+	sort.Strings(unbound)
+	sort.Slice(failed, func(i, j int) bool { return failed[i].app < failed[j].app })
+
+	return unbound, failed
+}
+
+func reportUnbindFailures(ui UI, unbound []string, failed []unbindFailure) {
+	if len(unbound) > 0 {
+		ui.Say("Successfully unbound: %s", strings.Join(unbound, ", "))
+	}
+
+	failedApps := make([]string, 0, len(failed))
+	for _, f := range failed {
+		failedApps = append(failedApps, fmt.Sprintf("%s (%s)", f.app, f.err))
+	}
+	ui.Say("Failed to unbind: %s", strings.Join(failedApps, ", "))
+}
+
+// purgeDrainResult is the --output json payload for PurgeDrains: one entry
+// per orphaned drain and whether it was deleted.
+type purgeDrainResult struct {
+	Name   string `json:"name"`
+	Guid   string `json:"guid,omitempty"`
+	Type   string `json:"type,omitempty"`
+	URL    string `json:"url,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// PurgeDrains removes every orphaned syslog drain user-provided service in
+// the targeted space (or, with --all-spaces, the targeted org). A drain is
+// orphaned when it is a syslog://, syslog-tls:// or https:// user-provided
+// service that is not bound to any app; an https:// service counts only when
+// it has a Type (see isPluginDrain). As with DeleteDrain, --output json
+// implies -f and writes a single JSON array summarizing the outcome instead
+// of the usual plain-text messages.
+func PurgeDrains(cli cliConnection, args []string, ui UI, df DrainFetcher) {
+	opts := struct {
+		Force     bool   `short:"f"`
+		AllSpaces bool   `long:"all-spaces"`
+		Output    string `short:"o" long:"output"`
+	}{}
+
+	_, err := flags.ParseArgs(&opts, args)
+	if err != nil {
+		failf(ui, opts.Output, "%s", err)
+	}
+
+	purgeDrains(cli, ui, df, opts.Force, opts.AllSpaces, opts.Output)
+}
+
+func purgeDrains(cli cliConnection, ui UI, df DrainFetcher, force, allSpaces bool, output string) {
+	jsonOutput := output == "json"
+
+	var drains []drain.Drain
+	var err error
+	if allSpaces {
+		drains, err = df.DrainsInOrg()
+	} else {
+		drains, err = df.Drains()
+	}
+	if err != nil {
+		failf(ui, output, "%s", err)
+	}
+
+	var orphaned []drain.Drain
+	for _, d := range drains {
+		u, err := url.Parse(d.DrainURL)
+		if err != nil || !orphanSchemes[u.Scheme] {
+			continue
+		}
+
+		if u.Scheme == "https" && !isPluginDrain(d) {
+			continue
+		}
+
+		if len(d.Apps) == 0 {
+			orphaned = append(orphaned, d)
+		}
+	}
+
+	if len(orphaned) == 0 {
+		if jsonOutput {
+			printJSON(ui, []purgeDrainResult{})
+			return
+		}
+		ui.Say("No orphaned drains found.")
+		return
+	}
+
+	names := make([]string, 0, len(orphaned))
+	for _, d := range orphaned {
+		names = append(names, d.Name)
+	}
+
+	if !force && !jsonOutput && !ui.Confirm(fmt.Sprintf(
+		"You are about to delete %d orphaned drains: %s. Are you sure? [y/N] ",
+		len(orphaned), strings.Join(names, ", "),
+	)) {
+		ui.Say("Delete cancelled")
+		return
+	}
+
+	results := make([]purgeDrainResult, 0, len(orphaned))
+	anyFailed := false
+	for _, d := range orphaned {
+		r := purgeDrainResult{Name: d.Name, Guid: d.Guid, Type: d.Type, URL: d.DrainURL}
+
+		if _, err := cli.CliCommand("delete-service", d.Name, "-f"); err != nil {
+			if !jsonOutput {
+				ui.Failed("%s", err)
+			}
+			r.Status = "failed"
+			r.Error = err.Error()
+			anyFailed = true
+			results = append(results, r)
+			continue
+		}
+
+		r.Status = "deleted"
+		results = append(results, r)
+	}
+
+	if jsonOutput {
+		if anyFailed {
+			ui.Failed("%s", mustJSON(results))
+			return
+		}
+		printJSON(ui, results)
+	}
+}
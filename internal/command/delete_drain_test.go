@@ -1,7 +1,7 @@
 package command_test
 
 import (
-	"bytes"
+	"encoding/json"
 	"errors"
 
 	"code.cloudfoundry.org/cf-drain-cli/internal/command"
@@ -13,20 +13,17 @@ import (
 var _ = Describe("DeleteDrain", func() {
 	var (
 		cli                 *stubCliConnection
-		logger              *stubLogger
-		reader              *bytes.Buffer
+		ui                  *command.FakeUI
 		serviceDrainFetcher *stubDrainFetcher
 	)
 
 	BeforeEach(func() {
-		logger = &stubLogger{}
+		ui = command.NewFakeUI()
 
 		cli = newStubCliConnection()
 		cli.getServicesName = "my-drain"
 		cli.getServicesApps = []string{"app-1", "app-2"}
 
-		reader = bytes.NewBuffer(nil)
-
 		serviceDrainFetcher = newStubDrainFetcher()
 	})
 
@@ -45,7 +42,7 @@ var _ = Describe("DeleteDrain", func() {
 				DrainURL: "syslog://drain.url.com",
 			})
 
-			command.DeleteDrain(cli, []string{"my-drain", "-f"}, logger, reader, serviceDrainFetcher)
+			command.DeleteDrain(cli, []string{"my-drain", "-f"}, ui, serviceDrainFetcher)
 
 			Expect(cli.cliCommandArgs).To(HaveLen(2))
 			Expect(cli.cliCommandArgs[0]).To(Equal([]string{
@@ -58,14 +55,12 @@ var _ = Describe("DeleteDrain", func() {
 	})
 
 	It("aborts if the user cancels the confirmation", func() {
-		reader.WriteString("no\n")
+		ui.Inputs = []string{"no"}
 
-		command.DeleteDrain(cli, []string{"my-drain"}, logger, reader, serviceDrainFetcher)
+		command.DeleteDrain(cli, []string{"my-drain"}, ui, serviceDrainFetcher)
 
-		Expect(logger.printMessages).To(ConsistOf(
+		Expect(ui.Outputs()).To(ConsistOf(
 			"Are you sure you want to unbind my-drain from app-1, app-2 and delete my-drain? [y/N] ",
-		))
-		Expect(logger.printfMessages).To(ConsistOf(
 			"Delete cancelled",
 		))
 
@@ -73,7 +68,7 @@ var _ = Describe("DeleteDrain", func() {
 	})
 
 	It("is not case sensitive with the confirmation", func() {
-		reader.WriteString("Y\n")
+		ui.Inputs = []string{"Y"}
 
 		serviceDrainFetcher.drains = append(serviceDrainFetcher.drains, drain.Drain{
 			Name:     "my-drain",
@@ -84,9 +79,9 @@ var _ = Describe("DeleteDrain", func() {
 			DrainURL: "syslog://drain.url.com",
 		})
 
-		command.DeleteDrain(cli, []string{"my-drain"}, logger, reader, serviceDrainFetcher)
+		command.DeleteDrain(cli, []string{"my-drain"}, ui, serviceDrainFetcher)
 
-		Expect(logger.printMessages).To(ConsistOf(
+		Expect(ui.Outputs()).To(ConsistOf(
 			"Are you sure you want to unbind my-drain from app-1, app-2 and delete my-drain? [y/N] ",
 		))
 
@@ -103,72 +98,162 @@ var _ = Describe("DeleteDrain", func() {
 	})
 
 	It("fatally logs with an incorrect number of arguments", func() {
-		reader.WriteString("y\n")
+		ui.Inputs = []string{"y"}
 
 		Expect(func() {
-			command.DeleteDrain(cli, []string{}, logger, reader, serviceDrainFetcher)
+			command.DeleteDrain(cli, []string{}, ui, serviceDrainFetcher)
 		}).To(Panic())
 
-		Expect(logger.fatalfMessage).To(Equal("Invalid arguments, expected 1, got 0."))
+		Expect(ui.FailedMessage()).To(Equal("Invalid arguments, expected 1, got 0."))
 
 		Expect(func() {
-			command.DeleteDrain(cli, []string{"one", "two"}, logger, reader, serviceDrainFetcher)
+			command.DeleteDrain(cli, []string{"one", "two"}, ui, serviceDrainFetcher)
 		}).To(Panic())
 
-		Expect(logger.fatalfMessage).To(Equal("Invalid arguments, expected 1, got 2."))
+		Expect(ui.FailedMessage()).To(Equal("Invalid arguments, expected 1, got 2."))
 	})
 
 	It("fatally logs for invalid flags", func() {
 		Expect(func() {
-			command.DeleteDrain(cli, []string{"some-drain", "--invalid"}, logger, reader, serviceDrainFetcher)
+			command.DeleteDrain(cli, []string{"some-drain", "--invalid"}, ui, serviceDrainFetcher)
 		}).To(Panic())
 
-		Expect(logger.fatalfMessage).To(Equal("unknown flag `invalid'"))
+		Expect(ui.FailedMessage()).To(Equal("unknown flag `invalid'"))
 	})
 
 	It("fatally logs when the service does not exist", func() {
-		reader.WriteString("y\n")
+		ui.Inputs = []string{"y"}
 
 		Expect(func() {
-			command.DeleteDrain(cli, []string{"not-a-service"}, logger, reader, serviceDrainFetcher)
+			command.DeleteDrain(cli, []string{"not-a-service"}, ui, serviceDrainFetcher)
 		}).To(Panic())
 
-		Expect(logger.fatalfMessage).To(Equal("Unable to find service not-a-service."))
+		Expect(ui.FailedMessage()).To(Equal("Unable to find service not-a-service."))
 	})
 
 	It("fatally logs when getting the services fails", func() {
-		reader.WriteString("y\n")
+		ui.Inputs = []string{"y"}
 
 		cli.getServicesError = errors.New("no get services")
 
 		Expect(func() {
-			command.DeleteDrain(cli, []string{"my-drain"}, logger, reader, serviceDrainFetcher)
+			command.DeleteDrain(cli, []string{"my-drain"}, ui, serviceDrainFetcher)
 		}).To(Panic())
 
-		Expect(logger.fatalfMessage).To(Equal("no get services"))
+		Expect(ui.FailedMessage()).To(Equal("no get services"))
 	})
 
 	It("fatally logs when unbinding a service fails", func() {
-		reader.WriteString("y\n")
+		ui.Inputs = []string{"y"}
 
 		cli.unbindServiceError = errors.New("unbind failed")
 
 		Expect(func() {
-			command.DeleteDrain(cli, []string{"my-drain"}, logger, reader, serviceDrainFetcher)
+			command.DeleteDrain(cli, []string{"my-drain"}, ui, serviceDrainFetcher)
 		}).To(Panic())
 
-		Expect(logger.fatalfMessage).To(Equal("unbind failed"))
+		Expect(ui.FailedMessage()).To(Equal("unbind failed"))
 	})
 
 	It("fatally logs when deleting the service fails", func() {
-		reader.WriteString("y\n")
+		ui.Inputs = []string{"y"}
 
 		cli.deleteServiceError = errors.New("delete failed")
 
 		Expect(func() {
-			command.DeleteDrain(cli, []string{"my-drain"}, logger, reader, serviceDrainFetcher)
+			command.DeleteDrain(cli, []string{"my-drain"}, ui, serviceDrainFetcher)
 		}).To(Panic())
 
-		Expect(logger.fatalfMessage).To(Equal("delete failed"))
+		Expect(ui.FailedMessage()).To(Equal("delete failed"))
+	})
+
+	Describe("--output json", func() {
+		BeforeEach(func() {
+			serviceDrainFetcher.drains = append(serviceDrainFetcher.drains, drain.Drain{
+				Name:     "my-drain",
+				Guid:     "my-drain-guid",
+				Apps:     []string{"app-1", "app-2"},
+				AppGuids: []string{"app-1-guid", "app-2-guid"},
+				Type:     "all",
+				DrainURL: "syslog://drain.url.com",
+			})
+		})
+
+		It("skips the prompt and prints a JSON summary", func() {
+			command.DeleteDrain(cli, []string{"my-drain", "--output", "json"}, ui, serviceDrainFetcher)
+
+			Expect(ui.Outputs()).To(HaveLen(1))
+
+			var result map[string]interface{}
+			Expect(json.Unmarshal([]byte(ui.Outputs()[0]), &result)).To(Succeed())
+			Expect(result["name"]).To(Equal("my-drain"))
+			Expect(result["guid"]).To(Equal("my-drain-guid"))
+			Expect(result["status"]).To(Equal("deleted"))
+
+			apps := result["apps"].([]interface{})
+			Expect(apps).To(HaveLen(2))
+		})
+
+		It("prints a single JSON object on the fatal path instead of a plain-text message", func() {
+			cli.deleteServiceError = errors.New("delete failed")
+
+			Expect(func() {
+				command.DeleteDrain(cli, []string{"my-drain", "--output", "json"}, ui, serviceDrainFetcher)
+			}).To(Panic())
+
+			Expect(ui.Outputs()).To(BeEmpty(), "no output besides the single failure object")
+
+			var result map[string]interface{}
+			Expect(json.Unmarshal([]byte(ui.FailedMessage()), &result)).To(Succeed())
+			Expect(result["name"]).To(Equal("my-drain"))
+			Expect(result["status"]).To(Equal("failed"))
+			Expect(result["error"]).To(Equal("delete failed"))
+		})
+	})
+
+	Describe("unbinding apps in parallel", func() {
+		BeforeEach(func() {
+			cli.getServicesApps = []string{"app-1", "app-2", "app-3", "app-4"}
+		})
+
+		It("unbinds every app, regardless of dispatch order, before deleting the service", func() {
+			ui.Inputs = []string{"y"}
+
+			command.DeleteDrain(cli, []string{"my-drain", "--parallel", "2"}, ui, serviceDrainFetcher)
+
+			Expect(cli.cliCommandArgs).To(HaveLen(5))
+			Expect(cli.cliCommandArgs).To(ConsistOf(
+				[]string{"unbind-service", "app-1", "my-drain"},
+				[]string{"unbind-service", "app-2", "my-drain"},
+				[]string{"unbind-service", "app-3", "my-drain"},
+				[]string{"unbind-service", "app-4", "my-drain"},
+				[]string{"delete-service", "my-drain", "-f"},
+			))
+
+			Expect(cli.cliCommandArgs[len(cli.cliCommandArgs)-1]).To(Equal(
+				[]string{"delete-service", "my-drain", "-f"},
+			))
+		})
+
+		It("aborts before deleting the service and reports successes and failures when an unbind fails", func() {
+			ui.Inputs = []string{"y"}
+
+			cli.unbindServiceErrors["app-3"] = errors.New("unbind failed: app-3")
+
+			Expect(func() {
+				command.DeleteDrain(cli, []string{"my-drain"}, ui, serviceDrainFetcher)
+			}).To(Panic())
+
+			Expect(ui.FailedMessage()).To(Equal("unbind failed: app-3"))
+			Expect(ui.Outputs()).To(ConsistOf(
+				"Are you sure you want to unbind my-drain from app-1, app-2, app-3, app-4 and delete my-drain? [y/N] ",
+				"Successfully unbound: app-1, app-2, app-4",
+				"Failed to unbind: app-3 (unbind failed: app-3)",
+			))
+
+			for _, args := range cli.cliCommandArgs {
+				Expect(args[0]).ToNot(Equal("delete-service"))
+			}
+		})
 	})
 })
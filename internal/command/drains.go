@@ -0,0 +1,60 @@
+package command
+
+import (
+	"strings"
+
+	flags "github.com/jessevdk/go-flags"
+)
+
+type drainsOptions struct {
+	Output string `short:"o" long:"output"`
+}
+
+type drainResult struct {
+	Name string   `json:"name"`
+	Guid string   `json:"guid"`
+	Type string   `json:"type"`
+	URL  string   `json:"url"`
+	Apps []string `json:"apps"`
+}
+
+// Drains lists the syslog drain user-provided services visible in the
+// targeted space. With --output json it writes a JSON array instead of the
+// usual plain-text table.
+func Drains(args []string, ui UI, df DrainFetcher) {
+	opts := drainsOptions{}
+	_, err := flags.ParseArgs(&opts, args)
+	if err != nil {
+		failf(ui, opts.Output, "%s", err)
+	}
+
+	drains, err := df.Drains()
+	if err != nil {
+		failf(ui, opts.Output, "%s", err)
+	}
+
+	if opts.Output == "json" {
+		results := make([]drainResult, 0, len(drains))
+		for _, d := range drains {
+			results = append(results, drainResult{
+				Name: d.Name,
+				Guid: d.Guid,
+				Type: d.Type,
+				URL:  d.DrainURL,
+				Apps: d.Apps,
+			})
+		}
+		printJSON(ui, results)
+		return
+	}
+
+	if len(drains) == 0 {
+		ui.Say("No drains found.")
+		return
+	}
+
+	ui.Say("name\ttype\turl\tapps")
+	for _, d := range drains {
+		ui.Say("%s\t%s\t%s\t%s", d.Name, d.Type, d.DrainURL, strings.Join(d.Apps, ", "))
+	}
+}
@@ -0,0 +1,61 @@
+package command_test
+
+import (
+	"encoding/json"
+	"errors"
+
+	"code.cloudfoundry.org/cf-drain-cli/internal/command"
+	"code.cloudfoundry.org/cf-drain-cli/internal/drain"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Drains", func() {
+	var (
+		ui                  *command.FakeUI
+		serviceDrainFetcher *stubDrainFetcher
+	)
+
+	BeforeEach(func() {
+		ui = command.NewFakeUI()
+		serviceDrainFetcher = newStubDrainFetcher()
+	})
+
+	It("prints a table of drains", func() {
+		serviceDrainFetcher.drains = []drain.Drain{
+			{Name: "my-drain", Guid: "my-drain-guid", Type: "all", DrainURL: "syslog://drain.url.com", Apps: []string{"app-1"}},
+		}
+
+		command.Drains([]string{}, ui, serviceDrainFetcher)
+
+		Expect(ui.Outputs()).To(ConsistOf(
+			"name\ttype\turl\tapps",
+			"my-drain\tall\tsyslog://drain.url.com\tapp-1",
+		))
+	})
+
+	It("prints the drains as JSON with --output json", func() {
+		serviceDrainFetcher.drains = []drain.Drain{
+			{Name: "my-drain", Guid: "my-drain-guid", Type: "all", DrainURL: "syslog://drain.url.com", Apps: []string{"app-1"}},
+		}
+
+		command.Drains([]string{"--output", "json"}, ui, serviceDrainFetcher)
+
+		Expect(ui.Outputs()).To(HaveLen(1))
+
+		var results []map[string]interface{}
+		Expect(json.Unmarshal([]byte(ui.Outputs()[0]), &results)).To(Succeed())
+		Expect(results).To(HaveLen(1))
+		Expect(results[0]["name"]).To(Equal("my-drain"))
+	})
+
+	It("fatally logs when fetching drains fails", func() {
+		serviceDrainFetcher.drainsError = errors.New("fetch failed")
+
+		Expect(func() {
+			command.Drains([]string{}, ui, serviceDrainFetcher)
+		}).To(Panic())
+
+		Expect(ui.FailedMessage()).To(Equal("fetch failed"))
+	})
+})
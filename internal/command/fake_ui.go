@@ -0,0 +1,66 @@
+package command
+
+import "fmt"
+
+// FakeUI is an in-memory UI for tests, replacing the stubLogger plus
+// bytes.Buffer combination the commands in this package used to be tested
+// with. It is exported so every command's tests share a single fake rather
+// than reimplementing one per file.
+type FakeUI struct {
+	// Inputs are consumed in order by Ask/Confirm.
+	Inputs []string
+
+	outputs  []string
+	warnings []string
+	failed   string
+}
+
+// NewFakeUI returns a FakeUI with no queued input.
+func NewFakeUI() *FakeUI {
+	return &FakeUI{}
+}
+
+func (u *FakeUI) Ask(prompt string) string {
+	u.outputs = append(u.outputs, prompt)
+
+	if len(u.Inputs) == 0 {
+		return ""
+	}
+
+	next := u.Inputs[0]
+	u.Inputs = u.Inputs[1:]
+	return next
+}
+
+func (u *FakeUI) Confirm(prompt string) bool {
+	resp := u.Ask(prompt)
+	return resp == "y" || resp == "Y"
+}
+
+func (u *FakeUI) Say(format string, args ...interface{}) {
+	u.outputs = append(u.outputs, fmt.Sprintf(format, args...))
+}
+
+func (u *FakeUI) Warn(format string, args ...interface{}) {
+	u.warnings = append(u.warnings, fmt.Sprintf(format, args...))
+}
+
+func (u *FakeUI) Failed(format string, args ...interface{}) {
+	u.failed = fmt.Sprintf(format, args...)
+	panic(u.failed)
+}
+
+// Outputs returns every prompt/message passed to Ask/Say, in order.
+func (u *FakeUI) Outputs() []string {
+	return u.outputs
+}
+
+// Warnings returns every message passed to Warn, in order.
+func (u *FakeUI) Warnings() []string {
+	return u.warnings
+}
+
+// FailedMessage returns the message passed to Failed, if any.
+func (u *FakeUI) FailedMessage() string {
+	return u.failed
+}
@@ -0,0 +1,148 @@
+package command_test
+
+import (
+	"encoding/json"
+	"errors"
+
+	"code.cloudfoundry.org/cf-drain-cli/internal/command"
+	"code.cloudfoundry.org/cf-drain-cli/internal/drain"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PurgeDrains", func() {
+	var (
+		cli                 *stubCliConnection
+		ui                  *command.FakeUI
+		serviceDrainFetcher *stubDrainFetcher
+	)
+
+	BeforeEach(func() {
+		cli = newStubCliConnection()
+		ui = command.NewFakeUI()
+		serviceDrainFetcher = newStubDrainFetcher()
+	})
+
+	It("deletes orphaned syslog drains and leaves bound ones alone", func() {
+		serviceDrainFetcher.drains = []drain.Drain{
+			{Name: "orphan-1", DrainURL: "syslog://drain.url.com"},
+			{Name: "orphan-2", DrainURL: "syslog-tls://drain.url.com"},
+			{Name: "orphan-3", Type: "all", DrainURL: "https://drain.url.com"},
+			{Name: "bound-drain", Apps: []string{"app-1"}, DrainURL: "https://drain.url.com"},
+			{Name: "not-a-drain", DrainURL: "https://some-other-service.com"},
+		}
+
+		command.PurgeDrains(cli, []string{"-f"}, ui, serviceDrainFetcher)
+
+		Expect(cli.cliCommandArgs).To(ConsistOf(
+			[]string{"delete-service", "orphan-1", "-f"},
+			[]string{"delete-service", "orphan-2", "-f"},
+			[]string{"delete-service", "orphan-3", "-f"},
+		))
+	})
+
+	It("does not purge an unbound https user-provided service that isn't one of this plugin's drains", func() {
+		serviceDrainFetcher.drains = []drain.Drain{
+			{Name: "not-a-drain", DrainURL: "https://some-other-service.com"},
+		}
+
+		command.PurgeDrains(cli, []string{"-f"}, ui, serviceDrainFetcher)
+
+		Expect(ui.Outputs()).To(ConsistOf("No orphaned drains found."))
+		Expect(cli.cliCommandArgs).To(HaveLen(0))
+	})
+
+	It("asks for confirmation before deleting", func() {
+		ui.Inputs = []string{"no"}
+
+		serviceDrainFetcher.drains = []drain.Drain{
+			{Name: "orphan-1", DrainURL: "syslog://drain.url.com"},
+		}
+
+		command.PurgeDrains(cli, []string{}, ui, serviceDrainFetcher)
+
+		Expect(ui.Outputs()).To(ConsistOf(
+			"You are about to delete 1 orphaned drains: orphan-1. Are you sure? [y/N] ",
+			"Delete cancelled",
+		))
+		Expect(cli.cliCommandArgs).To(HaveLen(0))
+	})
+
+	It("does nothing when there are no orphaned drains", func() {
+		serviceDrainFetcher.drains = []drain.Drain{
+			{Name: "bound-drain", Apps: []string{"app-1"}, DrainURL: "syslog://drain.url.com"},
+		}
+
+		command.PurgeDrains(cli, []string{"-f"}, ui, serviceDrainFetcher)
+
+		Expect(ui.Outputs()).To(ConsistOf("No orphaned drains found."))
+		Expect(cli.cliCommandArgs).To(HaveLen(0))
+	})
+
+	It("fetches org-wide drains with --all-spaces", func() {
+		serviceDrainFetcher.drains = []drain.Drain{
+			{Name: "space-orphan", DrainURL: "syslog://drain.url.com"},
+		}
+		serviceDrainFetcher.orgDrains = []drain.Drain{
+			{Name: "org-orphan", DrainURL: "syslog://drain.url.com"},
+		}
+
+		command.PurgeDrains(cli, []string{"-f", "--all-spaces"}, ui, serviceDrainFetcher)
+
+		Expect(cli.cliCommandArgs).To(ConsistOf(
+			[]string{"delete-service", "org-orphan", "-f"},
+		))
+	})
+
+	It("fatally logs when fetching drains fails", func() {
+		serviceDrainFetcher.drainsError = errors.New("fetch failed")
+
+		Expect(func() {
+			command.PurgeDrains(cli, []string{"-f"}, ui, serviceDrainFetcher)
+		}).To(Panic())
+
+		Expect(ui.FailedMessage()).To(Equal("fetch failed"))
+	})
+
+	Describe("--output json", func() {
+		It("skips the prompt and prints a single JSON summary of the deleted drains", func() {
+			serviceDrainFetcher.drains = []drain.Drain{
+				{Name: "orphan-1", Guid: "orphan-1-guid", Type: "all", DrainURL: "syslog://drain.url.com"},
+				{Name: "bound-drain", Apps: []string{"app-1"}, DrainURL: "https://drain.url.com"},
+			}
+
+			command.PurgeDrains(cli, []string{"--output", "json"}, ui, serviceDrainFetcher)
+
+			Expect(cli.cliCommandArgs).To(ConsistOf(
+				[]string{"delete-service", "orphan-1", "-f"},
+			))
+
+			Expect(ui.Outputs()).To(HaveLen(1))
+
+			var results []map[string]interface{}
+			Expect(json.Unmarshal([]byte(ui.Outputs()[0]), &results)).To(Succeed())
+			Expect(results).To(HaveLen(1))
+			Expect(results[0]["name"]).To(Equal("orphan-1"))
+			Expect(results[0]["status"]).To(Equal("deleted"))
+		})
+
+		It("prints a single JSON object on the fatal path instead of a plain-text message", func() {
+			serviceDrainFetcher.drains = []drain.Drain{
+				{Name: "orphan-1", DrainURL: "syslog://drain.url.com"},
+			}
+			cli.deleteServiceError = errors.New("delete failed")
+
+			Expect(func() {
+				command.PurgeDrains(cli, []string{"--output", "json"}, ui, serviceDrainFetcher)
+			}).To(Panic())
+
+			Expect(ui.Outputs()).To(BeEmpty(), "no output besides the single failure object")
+
+			var results []map[string]interface{}
+			Expect(json.Unmarshal([]byte(ui.FailedMessage()), &results)).To(Succeed())
+			Expect(results).To(HaveLen(1))
+			Expect(results[0]["status"]).To(Equal("failed"))
+			Expect(results[0]["error"]).To(Equal("delete failed"))
+		})
+	})
+})
@@ -0,0 +1,95 @@
+package command
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// UI is the prompting/output surface shared by every command in this
+// package, modeled on the upstream cf CLI's terminal.UI. It replaces the
+// ad-hoc Logger/io.Reader pair each command used to take individually.
+type UI interface {
+	// Ask prints prompt and returns the trimmed line of input that follows.
+	Ask(prompt string) string
+
+	// Confirm prints prompt and reports whether the response was "y"
+	// (case-insensitive).
+	Confirm(prompt string) bool
+
+	// Say prints an informational message.
+	Say(format string, args ...interface{})
+
+	// Warn prints a message calling out something the operator should
+	// pay attention to but that isn't fatal.
+	Warn(format string, args ...interface{})
+
+	// Failed prints an error message and aborts the command.
+	Failed(format string, args ...interface{})
+}
+
+// osExit is a seam over os.Exit so tests can exercise terminalUI.Failed
+// without killing the test process.
+var osExit = os.Exit
+
+type terminalUI struct {
+	in    *bufio.Reader
+	out   io.Writer
+	color bool
+}
+
+// NewUI builds the real, interactive UI used by main. Color is enabled
+// unless CF_COLOR=false or noColor is set, matching the upstream cf CLI's
+// terminal package.
+func NewUI(in io.Reader, out io.Writer, noColor bool) UI {
+	return &terminalUI{
+		in:    bufio.NewReader(in),
+		out:   out,
+		color: !noColor && !strings.EqualFold(os.Getenv("CF_COLOR"), "false"),
+	}
+}
+
+func (u *terminalUI) Ask(prompt string) string {
+	fmt.Fprint(u.out, prompt)
+
+	resp, _ := u.in.ReadString('\n')
+	return strings.TrimSpace(resp)
+}
+
+func (u *terminalUI) Confirm(prompt string) bool {
+	return strings.EqualFold(u.Ask(prompt), "y")
+}
+
+func (u *terminalUI) Say(format string, args ...interface{}) {
+	fmt.Fprintf(u.out, format+"\n", args...)
+}
+
+func (u *terminalUI) Warn(format string, args ...interface{}) {
+	fmt.Fprintln(u.out, u.colorize("33", fmt.Sprintf(format, args...)))
+}
+
+// Failed prints msg and exits 1. Callers that pass a JSON-encoded message
+// (the --output json commands) get the message on its own with no banner,
+// so stdout stays a single parseable JSON value; everything else gets the
+// usual colorized "FAILED" banner ahead of the message.
+func (u *terminalUI) Failed(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	if !looksLikeJSON(msg) {
+		fmt.Fprintln(u.out, u.colorize("31", "FAILED"))
+	}
+	fmt.Fprintln(u.out, msg)
+	osExit(1)
+}
+
+func looksLikeJSON(msg string) bool {
+	return strings.HasPrefix(msg, "{") || strings.HasPrefix(msg, "[")
+}
+
+func (u *terminalUI) colorize(code, msg string) string {
+	if !u.color {
+		return msg
+	}
+	return fmt.Sprintf("\x1b[%sm%s\x1b[0m", code, msg)
+}
@@ -0,0 +1,55 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestTerminalUIFailedJSON exercises the real terminalUI (not FakeUI) on the
+// --output json fatal path, since FakeUI stores the failure separately and
+// can't catch terminalUI writing a banner ahead of the JSON payload.
+func TestTerminalUIFailedJSON(t *testing.T) {
+	origExit := osExit
+	var exitCode int
+	osExit = func(code int) { exitCode = code }
+	defer func() { osExit = origExit }()
+
+	var out bytes.Buffer
+	ui := NewUI(strings.NewReader(""), &out, true)
+
+	ui.Failed("%s", mustJSON(map[string]string{"error": "boom"}))
+
+	if exitCode != 1 {
+		t.Fatalf("expected exit code 1, got %d", exitCode)
+	}
+
+	var result map[string]string
+	if err := json.Unmarshal(bytes.TrimSpace(out.Bytes()), &result); err != nil {
+		t.Fatalf("output was not a single JSON value: %v (got %q)", err, out.String())
+	}
+	if result["error"] != "boom" {
+		t.Fatalf("expected error %q, got %q", "boom", result["error"])
+	}
+}
+
+// TestTerminalUIFailedPlainText keeps the FAILED banner for the ordinary,
+// non-JSON fatal path.
+func TestTerminalUIFailedPlainText(t *testing.T) {
+	origExit := osExit
+	osExit = func(code int) {}
+	defer func() { osExit = origExit }()
+
+	var out bytes.Buffer
+	ui := NewUI(strings.NewReader(""), &out, true)
+
+	ui.Failed("%s", "boom")
+
+	if !strings.Contains(out.String(), "FAILED") {
+		t.Fatalf("expected banner in plain-text output, got %q", out.String())
+	}
+	if !strings.Contains(out.String(), "boom") {
+		t.Fatalf("expected message in output, got %q", out.String())
+	}
+}
@@ -0,0 +1,13 @@
+package drain
+
+// Drain represents a syslog (or syslog-tls/https) user-provided service
+// instance created by this plugin, along with the apps it is currently
+// bound to.
+type Drain struct {
+	Name     string
+	Guid     string
+	Apps     []string
+	AppGuids []string
+	Type     string
+	DrainURL string
+}